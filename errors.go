@@ -0,0 +1,62 @@
+package nadeo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned whenever Nadeo responds with a non-200 status. It
+// preserves the HTTP status code and, when the body parses as Nadeo's
+// standard {code, message} error envelope, the structured error code and
+// message too, so callers don't have to string-match fmt.Errorf output.
+type APIError struct {
+	StatusCode int
+	Code       int
+	Message    string
+	Body       []byte
+	URL        string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("error %d from server (http %d): %s", e.Code, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("error from server (http %d): %s", e.StatusCode, string(e.Body))
+}
+
+// newAPIError builds an APIError from a non-200 response, decoding body as
+// Nadeo's {code, message} error envelope when it parses as one.
+func newAPIError(statusCode int, url string, body []byte) *APIError {
+	respError := errorResponse{}
+	json.Unmarshal(body, &respError)
+
+	return &APIError{
+		StatusCode: statusCode,
+		Code:       respError.Code,
+		Message:    respError.Message,
+		Body:       body,
+		URL:        url,
+	}
+}
+
+// IsAuthError reports whether err is an *APIError caused by an expired or
+// otherwise invalid token (HTTP 401), meaning a refresh and retry may help.
+func IsAuthError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusUnauthorized
+	}
+	return false
+}
+
+// IsRateLimited reports whether err is an *APIError caused by exceeding
+// Nadeo's rate limits (HTTP 429).
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package nadeo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        []byte
+		wantCode    int
+		wantMessage string
+	}{
+		{
+			name:        "structured error envelope",
+			statusCode:  http.StatusUnauthorized,
+			body:        []byte(`{"code":401,"message":"Invalid credentials."}`),
+			wantCode:    401,
+			wantMessage: "Invalid credentials.",
+		},
+		{
+			name:        "non-JSON body",
+			statusCode:  http.StatusBadGateway,
+			body:        []byte("upstream timeout"),
+			wantCode:    0,
+			wantMessage: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAPIError(tt.statusCode, "https://example.com", tt.body)
+
+			if err.StatusCode != tt.statusCode {
+				t.Errorf("StatusCode = %d, want %d", err.StatusCode, tt.statusCode)
+			}
+			if err.Code != tt.wantCode {
+				t.Errorf("Code = %d, want %d", err.Code, tt.wantCode)
+			}
+			if err.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", err.Message, tt.wantMessage)
+			}
+			if err.Error() == "" {
+				t.Error("Error() returned an empty string")
+			}
+		})
+	}
+}
+
+func TestIsAuthErrorAndIsRateLimited(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		wantAuthError   bool
+		wantRateLimited bool
+	}{
+		{
+			name:          "401 is an auth error",
+			err:           newAPIError(http.StatusUnauthorized, "https://example.com", nil),
+			wantAuthError: true,
+		},
+		{
+			name:            "429 is rate limited",
+			err:             newAPIError(http.StatusTooManyRequests, "https://example.com", nil),
+			wantRateLimited: true,
+		},
+		{
+			name: "other status is neither",
+			err:  newAPIError(http.StatusBadRequest, "https://example.com", nil),
+		},
+		{
+			name: "non-APIError is neither",
+			err:  errors.New("boom"),
+		},
+		{
+			name:          "wrapped APIError still unwraps",
+			err:           fmt.Errorf("unable to refresh token: %w", newAPIError(http.StatusUnauthorized, "https://example.com", nil)),
+			wantAuthError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.err); got != tt.wantAuthError {
+				t.Errorf("IsAuthError() = %v, want %v", got, tt.wantAuthError)
+			}
+			if got := IsRateLimited(tt.err); got != tt.wantRateLimited {
+				t.Errorf("IsRateLimited() = %v, want %v", got, tt.wantRateLimited)
+			}
+		})
+	}
+}
@@ -2,51 +2,123 @@ package nadeo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 )
 
 // Nadeo provides access to the Nadeo Live Services API.
 type Nadeo interface {
 	AuthenticateUbi(email, password string) error
+	AuthenticateUbiContext(ctx context.Context, email, password string) error
 	AuthenticateUbiTicket(ticket string) error
+	AuthenticateUbiTicketContext(ctx context.Context, ticket string) error
 	Authenticate(username, password string) error
+	AuthenticateContext(ctx context.Context, username, password string) error
+	AuthenticateOAuth(clientID, clientSecret string) error
+	AuthenticateOAuthContext(ctx context.Context, clientID, clientSecret string) error
 	GetTokenInfo() TokenInfo
 
 	Get(url string, useCache bool) (string, error)
+	GetContext(ctx context.Context, url string, useCache bool) (string, error)
 	Post(url, data string) (string, error)
+	PostContext(ctx context.Context, url, data string) (string, error)
 
 	CheckRefresh() error
+	CheckRefreshContext(ctx context.Context) error
 }
 
 type nadeo struct {
 	audience string
+	authMode authMode
 
+	clientID     string
+	clientSecret string
+
+	tokenMu      sync.RWMutex
 	accessToken  string
 	refreshToken string
 
 	tokenRefreshTime    uint32
 	tokenExpirationTime uint32
 
+	refreshGroup        singleflight.Group
+	refreshSafetyMargin time.Duration
+	tokenStore          TokenStore
+
 	requestCache *cache.Cache
+
+	httpClient  *http.Client
+	maxRetries  int
+	rateLimiter *rate.Limiter
+}
+
+// setTokens atomically stores a freshly issued or refreshed access/refresh
+// token pair along with the access token's refresh-at and expiration times,
+// then persists it to the configured TokenStore.
+func (n *nadeo) setTokens(accessToken, refreshToken string, refreshTime, expirationTime uint32) {
+	n.tokenMu.Lock()
+	n.accessToken = accessToken
+	n.refreshToken = refreshToken
+	n.tokenRefreshTime = refreshTime
+	n.tokenExpirationTime = expirationTime
+	n.tokenMu.Unlock()
+
+	if n.tokenStore != nil {
+		_ = n.tokenStore.Save(n.audience, &Tokens{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			RefreshTime:  refreshTime,
+			ExpiryTime:   expirationTime,
+		})
+	}
+}
+
+func (n *nadeo) getAccessToken() string {
+	n.tokenMu.RLock()
+	defer n.tokenMu.RUnlock()
+	return n.accessToken
+}
+
+func (n *nadeo) getRefreshToken() string {
+	n.tokenMu.RLock()
+	defer n.tokenMu.RUnlock()
+	return n.refreshToken
+}
+
+func (n *nadeo) getTokenRefreshTime() uint32 {
+	n.tokenMu.RLock()
+	defer n.tokenMu.RUnlock()
+	return n.tokenRefreshTime
 }
 
 func (n *nadeo) AuthenticateUbi(email, password string) error {
+	return n.AuthenticateUbiContext(context.Background(), email, password)
+}
+
+func (n *nadeo) AuthenticateUbiContext(ctx context.Context, email, password string) error {
 	ubi := NewUbi("86263886-327a-4328-ac69-527f0d20a237")
 	ubi.Authenticate(email, password)
-	return n.AuthenticateUbiTicket(ubi.GetTicket())
+	return n.AuthenticateUbiTicketContext(ctx, ubi.GetTicket())
 }
 
 func (n *nadeo) AuthenticateUbiTicket(ticket string) error {
+	return n.AuthenticateUbiTicketContext(context.Background(), ticket)
+}
+
+func (n *nadeo) AuthenticateUbiTicketContext(ctx context.Context, ticket string) error {
 	body := bytes.NewReader([]byte("{\"audience\":\"" + n.audience + "\"}"))
 
-	req, err := http.NewRequest("POST", "https://prod.trackmania.core.nadeo.online/v2/authentication/token/ubiservices", body)
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://prod.trackmania.core.nadeo.online/v2/authentication/token/ubiservices", body)
 	if err != nil {
 		return fmt.Errorf("unable to make request: %s", err.Error())
 	}
@@ -54,38 +126,37 @@ func (n *nadeo) AuthenticateUbiTicket(ticket string) error {
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Authorization", "ubi_v1 t="+ticket)
 
-	client := http.Client{}
-	resp, err := client.Do(req)
+	resp, err := n.doWithRetry(req)
 	if err != nil {
-		return fmt.Errorf("unable to perform request: %s", err.Error())
+		return err
 	}
 
-	resBytes := make([]byte, resp.ContentLength)
-	io.ReadFull(resp.Body, resBytes)
+	resBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read from stream: %s", err.Error())
+	}
 
 	if resp.StatusCode != 200 {
-		respError := errorResponse{}
-		json.Unmarshal(resBytes, &respError)
-		return fmt.Errorf("error %d from server: %s", respError.Code, respError.Message)
+		return newAPIError(resp.StatusCode, req.URL.String(), resBytes)
 	}
 
 	res := authResponse{}
 	json.Unmarshal(resBytes, &res)
 
-	n.accessToken = res.AccessToken
-	n.refreshToken = res.RefreshToken
-
-	tokenInfo := parseTokenInfo(n.accessToken)
-	n.tokenRefreshTime = tokenInfo.Payload.Rat
-	n.tokenExpirationTime = tokenInfo.Payload.Exp
+	tokenInfo := parseTokenInfo(res.AccessToken)
+	n.setTokens(res.AccessToken, res.RefreshToken, tokenInfo.Payload.Rat, tokenInfo.Payload.Exp)
 
 	return nil
 }
 
 func (n *nadeo) Authenticate(username, password string) error {
+	return n.AuthenticateContext(context.Background(), username, password)
+}
+
+func (n *nadeo) AuthenticateContext(ctx context.Context, username, password string) error {
 	body := bytes.NewReader([]byte("{\"audience\":\"" + n.audience + "\"}"))
 
-	req, err := http.NewRequest("POST", "https://prod.trackmania.core.nadeo.online/v2/authentication/token/basic", body)
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://prod.trackmania.core.nadeo.online/v2/authentication/token/basic", body)
 	if err != nil {
 		return fmt.Errorf("unable to make request: %s", err.Error())
 	}
@@ -93,61 +164,81 @@ func (n *nadeo) Authenticate(username, password string) error {
 	req.Header.Add("Content-Type", "application/json")
 	req.SetBasicAuth(username, password)
 
-	client := http.Client{}
-	resp, err := client.Do(req)
+	resp, err := n.doWithRetry(req)
 	if err != nil {
-		return fmt.Errorf("unable to perform request: %s", err.Error())
+		return err
 	}
 
-	resBytes := make([]byte, resp.ContentLength)
-	io.ReadFull(resp.Body, resBytes)
+	resBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read from stream: %s", err.Error())
+	}
 
 	if resp.StatusCode != 200 {
-		respError := errorResponse{}
-		json.Unmarshal(resBytes, &respError)
 		// 401: "Username could not be found."  -> Invalid username
 		// 401: "Invalid credentials."          -> Invalid password
 		//   0: "There was a validation error." -> Invalid audience
-		return fmt.Errorf("error %d from server: %s", respError.Code, respError.Message)
+		return newAPIError(resp.StatusCode, req.URL.String(), resBytes)
 	}
 
 	res := authResponse{}
 	json.Unmarshal(resBytes, &res)
 
-	n.accessToken = res.AccessToken
-	n.refreshToken = res.RefreshToken
-
-	tokenInfo := parseTokenInfo(n.accessToken)
-	n.tokenRefreshTime = tokenInfo.Payload.Rat
-	n.tokenExpirationTime = tokenInfo.Payload.Exp
+	tokenInfo := parseTokenInfo(res.AccessToken)
+	n.setTokens(res.AccessToken, res.RefreshToken, tokenInfo.Payload.Rat, tokenInfo.Payload.Exp)
 
 	return nil
 }
 
 func (n *nadeo) GetTokenInfo() TokenInfo {
-	return parseTokenInfo(n.accessToken)
+	return parseTokenInfo(n.getAccessToken())
 }
 
 func (n *nadeo) Get(url string, useCache bool) (string, error) {
-	return n.request("GET", url, useCache, "")
+	return n.GetContext(context.Background(), url, useCache)
+}
+
+func (n *nadeo) GetContext(ctx context.Context, url string, useCache bool) (string, error) {
+	return n.request(ctx, "GET", url, useCache, "")
 }
 
 func (n *nadeo) Post(url, data string) (string, error) {
-	return n.request("POST", url, false, data)
+	return n.PostContext(context.Background(), url, data)
+}
+
+func (n *nadeo) PostContext(ctx context.Context, url, data string) (string, error) {
+	return n.request(ctx, "POST", url, false, data)
 }
 
 func (n *nadeo) CheckRefresh() error {
-	now := uint32(time.Now().Unix())
-	if now > n.tokenRefreshTime {
-		err := n.refreshNow()
-		if err != nil {
-			return fmt.Errorf("unable to refresh token: %s", err.Error())
+	return n.CheckRefreshContext(context.Background())
+}
+
+func (n *nadeo) CheckRefreshContext(ctx context.Context) error {
+	now := uint32(time.Now().Add(n.refreshSafetyMargin).Unix())
+	if now <= n.getTokenRefreshTime() {
+		return nil
+	}
+
+	// Coalesce concurrent refreshes triggered by requests that raced to the
+	// same expiry check into a single in-flight HTTP call; every waiter
+	// shares its result instead of each firing its own refresh POST. The
+	// refresh itself runs on a detached context rather than the triggering
+	// caller's ctx, so one caller canceling or timing out can't fail the
+	// refresh (and thus the Get/Post) for every other waiter sharing it.
+	_, err, _ := n.refreshGroup.Do("refresh", func() (interface{}, error) {
+		if n.authMode == authModeOAuth {
+			return nil, n.refreshOAuth(context.Background())
 		}
+		return nil, n.refreshNow(context.Background())
+	})
+	if err != nil {
+		return fmt.Errorf("unable to refresh token: %w", err)
 	}
 	return nil
 }
 
-func (n *nadeo) request(method string, url string, useCache bool, data string) (string, error) {
+func (n *nadeo) request(ctx context.Context, method string, url string, useCache bool, data string) (string, error) {
 	if useCache {
 		cachedResponse, cacheFound := n.requestCache.Get(url)
 		if cacheFound {
@@ -155,7 +246,7 @@ func (n *nadeo) request(method string, url string, useCache bool, data string) (
 		}
 	}
 
-	err := n.CheckRefresh()
+	err := n.CheckRefreshContext(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -165,19 +256,18 @@ func (n *nadeo) request(method string, url string, useCache bool, data string) (
 		body = bytes.NewReader([]byte(data))
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return "", fmt.Errorf("unable to make request: %s", err.Error())
 	}
 
-	req.Header.Add("Authorization", "nadeo_v1 t="+n.accessToken)
+	req.Header.Add("Authorization", n.authorizationHeader())
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json")
 
-	client := http.Client{}
-	resp, err := client.Do(req)
+	resp, err := n.doWithRetry(req)
 	if err != nil {
-		return "", fmt.Errorf("unable to perform request: %s", err.Error())
+		return "", err
 	}
 
 	resBytes, err := ioutil.ReadAll(resp.Body)
@@ -186,10 +276,7 @@ func (n *nadeo) request(method string, url string, useCache bool, data string) (
 	}
 
 	if resp.StatusCode != 200 {
-		//respError := errorResponse{}
-		//err := json.Unmarshal(resBytes, &respError)
-		return "", fmt.Errorf("error from server: %s", string(resBytes))
-		//return "", fmt.Errorf("error %d from server: %s", respError.Code, respError.Message)
+		return "", newAPIError(resp.StatusCode, req.URL.String(), resBytes)
 	}
 
 	if useCache {
@@ -199,38 +286,33 @@ func (n *nadeo) request(method string, url string, useCache bool, data string) (
 	return string(resBytes), nil
 }
 
-func (n *nadeo) refreshNow() error {
-	req, err := http.NewRequest("POST", "https://prod.trackmania.core.nadeo.online/v2/authentication/token/refresh", nil)
+func (n *nadeo) refreshNow(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://prod.trackmania.core.nadeo.online/v2/authentication/token/refresh", nil)
 	if err != nil {
 		return fmt.Errorf("unable to make request: %s", err.Error())
 	}
 
-	req.Header.Add("Authorization", "nadeo_v1 t="+n.refreshToken)
+	req.Header.Add("Authorization", "nadeo_v1 t="+n.getRefreshToken())
 
-	client := http.Client{}
-	resp, err := client.Do(req)
+	resp, err := n.doWithRetry(req)
 	if err != nil {
-		return fmt.Errorf("unable to perform request: %s", err.Error())
+		return err
 	}
 
-	resBytes := make([]byte, resp.ContentLength)
-	io.ReadFull(resp.Body, resBytes)
+	resBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read from stream: %s", err.Error())
+	}
 
 	if resp.StatusCode != 200 {
-		respError := errorResponse{}
-		json.Unmarshal(resBytes, &respError)
-		return fmt.Errorf("error %d from server: %s", respError.Code, respError.Message)
+		return newAPIError(resp.StatusCode, req.URL.String(), resBytes)
 	}
 
 	res := authResponse{}
 	json.Unmarshal(resBytes, &res)
 
-	n.accessToken = res.AccessToken
-	n.refreshToken = res.RefreshToken
-
-	tokenInfo := parseTokenInfo(n.accessToken)
-	n.tokenRefreshTime = tokenInfo.Payload.Rat
-	n.tokenExpirationTime = tokenInfo.Payload.Exp
+	tokenInfo := parseTokenInfo(res.AccessToken)
+	n.setTokens(res.AccessToken, res.RefreshToken, tokenInfo.Payload.Rat, tokenInfo.Payload.Exp)
 
 	return nil
 }
@@ -242,8 +324,31 @@ func NewNadeo() Nadeo {
 
 // NewNadeoWithAudience creates a new Nadeo object ready for authentication with the given audience.
 func NewNadeoWithAudience(audience string) Nadeo {
-	return &nadeo{
-		audience:     audience,
-		requestCache: cache.New(1*time.Minute, 5*time.Minute),
+	return NewNadeoWithOptions(Options{Audience: audience})
+}
+
+// NewNadeoWithOptions creates a new Nadeo object ready for authentication,
+// using opts to configure its HTTP transport: retry/backoff behaviour, a
+// rate limiter, connection pool tuning, or a caller-supplied http.Client.
+func NewNadeoWithOptions(opts Options) Nadeo {
+	opts = opts.withDefaults()
+
+	n := &nadeo{
+		audience:            opts.Audience,
+		requestCache:        cache.New(1*time.Minute, 5*time.Minute),
+		httpClient:          opts.HTTPClient,
+		maxRetries:          opts.MaxRetries,
+		rateLimiter:         opts.RateLimiter,
+		refreshSafetyMargin: opts.RefreshSafetyMargin,
+		tokenStore:          opts.TokenStore,
 	}
+
+	if t, err := opts.TokenStore.Load(opts.Audience); err == nil {
+		n.accessToken = t.AccessToken
+		n.refreshToken = t.RefreshToken
+		n.tokenRefreshTime = t.RefreshTime
+		n.tokenExpirationTime = t.ExpiryTime
+	}
+
+	return n
 }
@@ -0,0 +1,109 @@
+package nadeo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const oauthTokenURL = "https://api.trackmania.com/api/access_token"
+
+// authMode selects which authorization scheme request() uses and how
+// CheckRefresh renews an expiring token.
+type authMode int
+
+const (
+	authModeDedicatedServer authMode = iota
+	authModeOAuth
+)
+
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   uint32 `json:"expires_in"`
+}
+
+// AuthenticateOAuth authenticates against Nadeo's OAuth2 client-credentials
+// endpoint (api.trackmania.com), for third-party apps that have no
+// dedicated server account. Unlike AuthenticateUbi/Authenticate this flow
+// issues no refresh token; CheckRefresh re-runs the client-credentials
+// grant when the access token nears expiry.
+func (n *nadeo) AuthenticateOAuth(clientID, clientSecret string) error {
+	return n.AuthenticateOAuthContext(context.Background(), clientID, clientSecret)
+}
+
+// AuthenticateOAuthContext is AuthenticateOAuth with a caller-supplied
+// context.
+func (n *nadeo) AuthenticateOAuthContext(ctx context.Context, clientID, clientSecret string) error {
+	n.clientID = clientID
+	n.clientSecret = clientSecret
+	n.authMode = authModeOAuth
+
+	return n.refreshOAuth(ctx)
+}
+
+// refreshOAuth runs the client-credentials grant to obtain a new access
+// token. It is what CheckRefresh calls instead of refreshNow when the
+// client was authenticated via AuthenticateOAuth.
+func (n *nadeo) refreshOAuth(ctx context.Context) error {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", n.clientID)
+	form.Set("client_secret", n.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", oauthTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("unable to make request: %s", err.Error())
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := n.doWithRetry(req)
+	if err != nil {
+		return err
+	}
+
+	resBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read from stream: %s", err.Error())
+	}
+
+	if resp.StatusCode != 200 {
+		return newAPIError(resp.StatusCode, req.URL.String(), resBytes)
+	}
+
+	res := oauthTokenResponse{}
+	json.Unmarshal(resBytes, &res)
+
+	now := uint32(time.Now().Unix())
+	n.setTokens(res.AccessToken, "", now+res.ExpiresIn, now+res.ExpiresIn)
+
+	return nil
+}
+
+// authorizationHeader returns the Authorization header value for the
+// client's current auth mode and access token.
+func (n *nadeo) authorizationHeader() string {
+	if n.authMode == authModeOAuth {
+		return "Bearer " + n.getAccessToken()
+	}
+	return "nadeo_v1 t=" + n.getAccessToken()
+}
+
+// NewNadeoOAuth creates a new Nadeo object that authenticates via the
+// OAuth2 client-credentials flow instead of the dedicated-server
+// ubiservices flow. Call AuthenticateOAuth to complete authentication.
+func NewNadeoOAuth() Nadeo {
+	return NewNadeoOAuthWithOptions(Options{})
+}
+
+// NewNadeoOAuthWithOptions is NewNadeoOAuth with custom transport options.
+func NewNadeoOAuthWithOptions(opts Options) Nadeo {
+	n := NewNadeoWithOptions(opts).(*nadeo)
+	n.authMode = authModeOAuth
+	return n
+}
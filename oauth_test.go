@@ -0,0 +1,106 @@
+package nadeo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAuthenticateOAuthContextParsesTokenResponse(t *testing.T) {
+	var gotGrantType, gotClientID, gotClientSecret, gotContentType string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("unable to parse request form: %s", err.Error())
+		}
+		gotGrantType = r.PostForm.Get("grant_type")
+		gotClientID = r.PostForm.Get("client_id")
+		gotClientSecret = r.PostForm.Get("client_secret")
+		gotContentType = r.Header.Get("Content-Type")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"oauth-access-token","token_type":"Bearer","expires_in":21599}`))
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unable to parse server URL: %s", err.Error())
+	}
+
+	n := &nadeo{httpClient: &http.Client{Transport: &redirectTransport{target: target}}, maxRetries: defaultMaxRetries}
+
+	before := uint32(time.Now().Unix())
+	if err := n.AuthenticateOAuth("my-client-id", "my-client-secret"); err != nil {
+		t.Fatalf("AuthenticateOAuth returned an error: %s", err.Error())
+	}
+
+	if gotGrantType != "client_credentials" {
+		t.Errorf("grant_type = %q, want client_credentials", gotGrantType)
+	}
+	if gotClientID != "my-client-id" {
+		t.Errorf("client_id = %q, want my-client-id", gotClientID)
+	}
+	if gotClientSecret != "my-client-secret" {
+		t.Errorf("client_secret = %q, want my-client-secret", gotClientSecret)
+	}
+	if gotContentType != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q, want application/x-www-form-urlencoded", gotContentType)
+	}
+
+	if n.authMode != authModeOAuth {
+		t.Errorf("authMode = %v, want authModeOAuth", n.authMode)
+	}
+	if got := n.getAccessToken(); got != "oauth-access-token" {
+		t.Errorf("access token = %q, want oauth-access-token", got)
+	}
+	if got := n.authorizationHeader(); got != "Bearer oauth-access-token" {
+		t.Errorf("authorizationHeader() = %q, want %q", got, "Bearer oauth-access-token")
+	}
+
+	wantExpiry := before + 21599
+	if n.tokenExpirationTime < wantExpiry || n.tokenExpirationTime > wantExpiry+5 {
+		t.Errorf("tokenExpirationTime = %d, want ~%d", n.tokenExpirationTime, wantExpiry)
+	}
+	if n.tokenRefreshTime != n.tokenExpirationTime {
+		t.Errorf("tokenRefreshTime = %d, want it to equal tokenExpirationTime %d", n.tokenRefreshTime, n.tokenExpirationTime)
+	}
+}
+
+func TestCheckRefreshContextReRunsOAuthGrantOnExpiry(t *testing.T) {
+	var refreshCalls int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		refreshCalls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"refreshed-oauth-token","token_type":"Bearer","expires_in":21599}`))
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unable to parse server URL: %s", err.Error())
+	}
+
+	n := &nadeo{
+		httpClient:       &http.Client{Transport: &redirectTransport{target: target}},
+		maxRetries:       defaultMaxRetries,
+		authMode:         authModeOAuth,
+		clientID:         "my-client-id",
+		clientSecret:     "my-client-secret",
+		tokenRefreshTime: uint32(time.Now().Add(-time.Hour).Unix()),
+	}
+
+	if err := n.CheckRefresh(); err != nil {
+		t.Fatalf("CheckRefresh returned an error: %s", err.Error())
+	}
+
+	if refreshCalls != 1 {
+		t.Fatalf("expected exactly 1 refresh call, got %d", refreshCalls)
+	}
+	if got := n.getAccessToken(); got != "refreshed-oauth-token" {
+		t.Errorf("access token = %q, want refreshed-oauth-token", got)
+	}
+}
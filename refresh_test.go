@@ -0,0 +1,107 @@
+package nadeo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// redirectTransport forces every request onto target regardless of the
+// scheme/host baked into the request URL, so the hardcoded production
+// endpoints used by refreshNow/refreshOAuth can be exercised against an
+// httptest.Server.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// fakeAccessToken builds a syntactically valid JWT whose payload decodes to
+// the given refresh/expiration times, good enough for parseTokenInfo.
+func fakeAccessToken(t *testing.T, refreshTime, expirationTime uint32) string {
+	t.Helper()
+
+	payload, err := json.Marshal(struct {
+		Rat uint32 `json:"rat"`
+		Exp uint32 `json:"exp"`
+	}{Rat: refreshTime, Exp: expirationTime})
+	if err != nil {
+		t.Fatalf("unable to marshal fake token payload: %s", err.Error())
+	}
+
+	return "header." + base64.RawURLEncoding.EncodeToString(payload) + ".signature"
+}
+
+// TestCheckRefreshContextCoalescesConcurrentRefreshes guards the concurrency
+// claim from chunk0-3: many Get/Post callers racing an expired token should
+// trigger exactly one in-flight refresh POST, with every caller sharing its
+// result, instead of each firing its own refresh.
+func TestCheckRefreshContextCoalescesConcurrentRefreshes(t *testing.T) {
+	var refreshCalls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&refreshCalls, 1)
+
+		// Give every goroutine's expiry check a chance to race into
+		// CheckRefreshContext before this first refresh completes.
+		time.Sleep(50 * time.Millisecond)
+
+		res := authResponse{
+			AccessToken:  fakeAccessToken(t, uint32(time.Now().Add(time.Hour).Unix()), uint32(time.Now().Add(2*time.Hour).Unix())),
+			RefreshToken: "new-refresh-token",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unable to parse server URL: %s", err.Error())
+	}
+
+	expired := uint32(time.Now().Add(-time.Hour).Unix())
+	n := &nadeo{
+		httpClient:       &http.Client{Transport: &redirectTransport{target: target}},
+		maxRetries:       defaultMaxRetries,
+		tokenRefreshTime: expired,
+		accessToken:      fakeAccessToken(t, expired, expired),
+		refreshToken:     "stale-refresh-token",
+	}
+
+	const concurrency = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- n.CheckRefreshContext(context.Background())
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("CheckRefreshContext returned an error: %s", err.Error())
+		}
+	}
+
+	if got := atomic.LoadInt32(&refreshCalls); got != 1 {
+		t.Fatalf("expected exactly 1 refresh call, got %d", got)
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/N0tExisting/gonadeo"
+)
+
+const coreBaseURL = "https://prod.trackmania.core.nadeo.online"
+
+// CoreService exposes typed bindings for the NadeoServices core endpoints.
+type CoreService struct {
+	n nadeo.Nadeo
+}
+
+// accountDisplayName is a single entry in the GetAccountDisplayNames
+// response.
+type accountDisplayName struct {
+	AccountID   string `json:"accountId"`
+	DisplayName string `json:"displayName"`
+}
+
+// GetAccountDisplayNames resolves account IDs to their current display
+// names, keyed by account ID.
+func (s *CoreService) GetAccountDisplayNames(ctx context.Context, ids ...string) (map[string]string, error) {
+	url := fmt.Sprintf("%s/accounts/displayNames/?accountIdList=%s", coreBaseURL, strings.Join(ids, ","))
+
+	body, err := s.n.GetContext(ctx, url, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []accountDisplayName
+	if err := json.Unmarshal([]byte(body), &entries); err != nil {
+		return nil, fmt.Errorf("unable to parse account display names: %s", err.Error())
+	}
+
+	names := make(map[string]string, len(entries))
+	for _, e := range entries {
+		names[e.AccountID] = e.DisplayName
+	}
+	return names, nil
+}
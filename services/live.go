@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/N0tExisting/gonadeo"
+)
+
+const liveBaseURL = "https://live-services.trackmania.nadeo.live"
+
+// LiveService exposes typed bindings for the NadeoLiveServices endpoints.
+type LiveService struct {
+	n nadeo.Nadeo
+}
+
+// SeasonalCampaignList is the paginated response of GetSeasonalCampaigns.
+type SeasonalCampaignList struct {
+	ItemCount    int                `json:"itemCount"`
+	CampaignList []SeasonalCampaign `json:"campaignList"`
+}
+
+// SeasonalCampaign is a single seasonal (official) campaign.
+type SeasonalCampaign struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	ClubID int    `json:"clubId"`
+}
+
+// GetSeasonalCampaigns returns the paginated list of official seasonal
+// campaigns, offset and length controlling the page.
+func (s *LiveService) GetSeasonalCampaigns(ctx context.Context, offset, length int) (*SeasonalCampaignList, error) {
+	url := fmt.Sprintf("%s/api/token/campaign/official?offset=%d&length=%d", liveBaseURL, offset, length)
+
+	body, err := s.n.GetContext(ctx, url, false)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SeasonalCampaignList{}
+	if err := json.Unmarshal([]byte(body), res); err != nil {
+		return nil, fmt.Errorf("unable to parse seasonal campaigns: %s", err.Error())
+	}
+	return res, nil
+}
+
+// MapRecord is a single player's best time on a map.
+type MapRecord struct {
+	AccountID string `json:"accountId"`
+	MapID     string `json:"mapId"`
+	Time      int    `json:"time"`
+}
+
+// GetMapRecords returns the personal best records for accountIds on
+// mapUids.
+func (s *LiveService) GetMapRecords(ctx context.Context, mapUids []string, accountIds []string) ([]MapRecord, error) {
+	url := fmt.Sprintf("%s/api/token/leaderboard/group/map?mapList=%s&accountIdList=%s",
+		liveBaseURL, strings.Join(mapUids, ","), strings.Join(accountIds, ","))
+
+	body, err := s.n.GetContext(ctx, url, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []MapRecord
+	if err := json.Unmarshal([]byte(body), &res); err != nil {
+		return nil, fmt.Errorf("unable to parse map records: %s", err.Error())
+	}
+	return res, nil
+}
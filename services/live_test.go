@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/N0tExisting/gonadeo"
+)
+
+// fakeNadeo implements nadeo.Nadeo with a configurable GetContext, enough
+// to exercise the services package's JSON unmarshaling without a real
+// Nadeo client or network access.
+type fakeNadeo struct {
+	getContextFn func(ctx context.Context, url string, useCache bool) (string, error)
+}
+
+func (f *fakeNadeo) AuthenticateUbi(email, password string) error { return nil }
+func (f *fakeNadeo) AuthenticateUbiContext(ctx context.Context, email, password string) error {
+	return nil
+}
+func (f *fakeNadeo) AuthenticateUbiTicket(ticket string) error { return nil }
+func (f *fakeNadeo) AuthenticateUbiTicketContext(ctx context.Context, ticket string) error {
+	return nil
+}
+func (f *fakeNadeo) Authenticate(username, password string) error { return nil }
+func (f *fakeNadeo) AuthenticateContext(ctx context.Context, username, password string) error {
+	return nil
+}
+func (f *fakeNadeo) AuthenticateOAuth(clientID, clientSecret string) error { return nil }
+func (f *fakeNadeo) AuthenticateOAuthContext(ctx context.Context, clientID, clientSecret string) error {
+	return nil
+}
+func (f *fakeNadeo) GetTokenInfo() nadeo.TokenInfo { return nadeo.TokenInfo{} }
+func (f *fakeNadeo) Get(url string, useCache bool) (string, error) {
+	return f.GetContext(context.Background(), url, useCache)
+}
+func (f *fakeNadeo) GetContext(ctx context.Context, url string, useCache bool) (string, error) {
+	return f.getContextFn(ctx, url, useCache)
+}
+func (f *fakeNadeo) Post(url, data string) (string, error) { return "", nil }
+func (f *fakeNadeo) PostContext(ctx context.Context, url, data string) (string, error) {
+	return "", nil
+}
+func (f *fakeNadeo) CheckRefresh() error                          { return nil }
+func (f *fakeNadeo) CheckRefreshContext(ctx context.Context) error { return nil }
+
+func TestGetSeasonalCampaignsParsesResponse(t *testing.T) {
+	body := `{"itemCount":1,"campaignList":[{"id":42,"name":"Summer 2024","clubId":7}]}`
+
+	n := &fakeNadeo{getContextFn: func(ctx context.Context, url string, useCache bool) (string, error) {
+		return body, nil
+	}}
+
+	got, err := New(n).Live().GetSeasonalCampaigns(context.Background(), 0, 10)
+	if err != nil {
+		t.Fatalf("GetSeasonalCampaigns returned an error: %s", err.Error())
+	}
+
+	if got.ItemCount != 1 || len(got.CampaignList) != 1 {
+		t.Fatalf("unexpected response shape: %+v", got)
+	}
+	if got.CampaignList[0].ID != 42 || got.CampaignList[0].Name != "Summer 2024" || got.CampaignList[0].ClubID != 7 {
+		t.Fatalf("unexpected campaign: %+v", got.CampaignList[0])
+	}
+}
+
+func TestGetMapRecordsParsesResponse(t *testing.T) {
+	body := `[{"accountId":"acc-1","mapId":"map-1","time":12345}]`
+
+	n := &fakeNadeo{getContextFn: func(ctx context.Context, url string, useCache bool) (string, error) {
+		return body, nil
+	}}
+
+	got, err := New(n).Live().GetMapRecords(context.Background(), []string{"map-1"}, []string{"acc-1"})
+	if err != nil {
+		t.Fatalf("GetMapRecords returned an error: %s", err.Error())
+	}
+
+	if len(got) != 1 || got[0].AccountID != "acc-1" || got[0].Time != 12345 {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+}
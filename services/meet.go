@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/N0tExisting/gonadeo"
+)
+
+const meetBaseURL = "https://meet.trackmania.nadeo.club"
+
+// MeetService exposes typed bindings for the "meet" (Cup of the Day)
+// endpoints.
+type MeetService struct {
+	n nadeo.Nadeo
+}
+
+// Competition is a single Cup of the Day competition.
+type Competition struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	StartDate    int    `json:"startDate"`
+	EndDate      int    `json:"endDate"`
+	Participants int    `json:"participants"`
+}
+
+// GetCotdCurrent returns the currently running Cup of the Day competition.
+func (s *MeetService) GetCotdCurrent(ctx context.Context) (*Competition, error) {
+	body, err := s.n.GetContext(ctx, meetBaseURL+"/api/cup-of-the-day/current", false)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Competition{}
+	if err := json.Unmarshal([]byte(body), res); err != nil {
+		return nil, fmt.Errorf("unable to parse current cup of the day: %s", err.Error())
+	}
+	return res, nil
+}
@@ -0,0 +1,38 @@
+// Package services layers typed bindings over the raw Nadeo transport in
+// the parent package, so callers get strongly-typed request/response
+// structs and URL-building instead of hand-building URLs and re-parsing JSON
+// themselves. It covers a handful of Live/Meet/Core endpoints: seasonal
+// campaigns, map records, the current Cup of the Day and account display
+// names. Other endpoints are not bound here and are called through the raw
+// Get/Post methods.
+package services
+
+import (
+	"github.com/N0tExisting/gonadeo"
+)
+
+// Client wraps a nadeo.Nadeo with typed bindings grouped by Nadeo audience.
+type Client struct {
+	n nadeo.Nadeo
+}
+
+// New wraps an already-authenticated Nadeo client with typed bindings.
+func New(n nadeo.Nadeo) *Client {
+	return &Client{n: n}
+}
+
+// Live returns typed bindings for the NadeoLiveServices endpoints.
+func (c *Client) Live() *LiveService {
+	return &LiveService{n: c.n}
+}
+
+// Meet returns typed bindings for the NadeoClubServices "meet" (COTD)
+// endpoints.
+func (c *Client) Meet() *MeetService {
+	return &MeetService{n: c.n}
+}
+
+// Core returns typed bindings for the NadeoServices core endpoints.
+func (c *Client) Core() *CoreService {
+	return &CoreService{n: c.n}
+}
@@ -0,0 +1,190 @@
+package nadeo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Tokens is the access/refresh token pair and its associated timing
+// information, as persisted by a TokenStore.
+type Tokens struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	RefreshTime  uint32 `json:"refreshTime"`
+	ExpiryTime   uint32 `json:"expiryTime"`
+}
+
+// TokenStore persists tokens across process restarts, keyed by audience, so
+// short-lived CLI invocations don't need to re-authenticate against Ubisoft
+// on every run.
+type TokenStore interface {
+	// Load returns the tokens previously saved for audience. It returns an
+	// error if no tokens have been saved yet.
+	Load(audience string) (*Tokens, error)
+
+	// Save persists t for audience, overwriting whatever was saved before.
+	Save(audience string, t *Tokens) error
+}
+
+// memoryTokenStore keeps tokens in memory for the lifetime of the process.
+// It is the default TokenStore and provides no persistence across restarts.
+type memoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]*Tokens
+}
+
+// NewMemoryTokenStore creates a TokenStore that holds tokens in memory only.
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{tokens: make(map[string]*Tokens)}
+}
+
+func (s *memoryTokenStore) Load(audience string) (*Tokens, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tokens[audience]
+	if !ok {
+		return nil, fmt.Errorf("no tokens stored for audience %q", audience)
+	}
+	return t, nil
+}
+
+func (s *memoryTokenStore) Save(audience string, t *Tokens) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[audience] = t
+	return nil
+}
+
+// fileTokenStore persists tokens for every audience as JSON in a single
+// file on disk.
+type fileTokenStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileTokenStore creates a TokenStore backed by a JSON file at path. The
+// file is created on the first Save and holds tokens for every audience
+// that has been saved through this store.
+func NewFileTokenStore(path string) TokenStore {
+	return &fileTokenStore{path: path}
+}
+
+func (s *fileTokenStore) Load(audience string) (*Tokens, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := all[audience]
+	if !ok {
+		return nil, fmt.Errorf("no tokens stored for audience %q", audience)
+	}
+	return t, nil
+}
+
+func (s *fileTokenStore) Save(audience string, t *Tokens) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		all = make(map[string]*Tokens)
+	}
+	all[audience] = t
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal tokens: %s", err.Error())
+	}
+
+	// Write to a temp file in the same directory and rename it over path,
+	// so a crash or a concurrent writer never leaves a truncated/corrupt
+	// token file behind: the rename is atomic, the old file stays intact
+	// until it succeeds.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("unable to create temp token file: %s", err.Error())
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to write temp token file: %s", err.Error())
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to close temp token file: %s", err.Error())
+	}
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to set permissions on temp token file: %s", err.Error())
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to replace token file: %s", err.Error())
+	}
+	return nil
+}
+
+func (s *fileTokenStore) readAll() (map[string]*Tokens, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token file: %s", err.Error())
+	}
+
+	all := make(map[string]*Tokens)
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("unable to parse token file: %s", err.Error())
+	}
+	return all, nil
+}
+
+// keyringTokenStore persists tokens in the OS-provided credential store
+// (Keychain, Secret Service, Credential Manager) via go-keyring, keyed by
+// service name and audience.
+type keyringTokenStore struct {
+	service string
+}
+
+// NewKeyringTokenStore creates a TokenStore backed by the OS keyring, filed
+// under service.
+func NewKeyringTokenStore(service string) TokenStore {
+	return &keyringTokenStore{service: service}
+}
+
+func (s *keyringTokenStore) Load(audience string) (*Tokens, error) {
+	data, err := keyring.Get(s.service, audience)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read tokens from keyring: %s", err.Error())
+	}
+
+	t := &Tokens{}
+	if err := json.Unmarshal([]byte(data), t); err != nil {
+		return nil, fmt.Errorf("unable to parse tokens from keyring: %s", err.Error())
+	}
+	return t, nil
+}
+
+func (s *keyringTokenStore) Save(audience string, t *Tokens) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("unable to marshal tokens: %s", err.Error())
+	}
+
+	if err := keyring.Set(s.service, audience, string(data)); err != nil {
+		return fmt.Errorf("unable to write tokens to keyring: %s", err.Error())
+	}
+	return nil
+}
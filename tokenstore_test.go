@@ -0,0 +1,43 @@
+package nadeo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileTokenStoreSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	store := NewFileTokenStore(path)
+
+	want := &Tokens{AccessToken: "access", RefreshToken: "refresh", RefreshTime: 100, ExpiryTime: 200}
+	if err := store.Save("NadeoLiveServices", want); err != nil {
+		t.Fatalf("Save returned an error: %s", err.Error())
+	}
+
+	got, err := store.Load("NadeoLiveServices")
+	if err != nil {
+		t.Fatalf("Load returned an error: %s", err.Error())
+	}
+
+	if *got != *want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestFileTokenStoreSaveDoesNotLeaveTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tokens.json")
+	store := NewFileTokenStore(path)
+
+	if err := store.Save("NadeoLiveServices", &Tokens{AccessToken: "access"}); err != nil {
+		t.Fatalf("Save returned an error: %s", err.Error())
+	}
+
+	entries, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatalf("unable to glob temp dir: %s", err.Error())
+	}
+	if len(entries) != 1 || entries[0] != path {
+		t.Fatalf("expected only %q to exist, got %v", path, entries)
+	}
+}
@@ -0,0 +1,160 @@
+package nadeo
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultMaxRetries          = 3
+	defaultMaxIdleConns        = 100
+	defaultMaxConnsPerHost     = 10
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultRefreshSafetyMargin = 30 * time.Second
+)
+
+// Options configures the transport and authentication behaviour of a Nadeo
+// client. Pass it to NewNadeoWithOptions; the zero value is filled in with
+// the same defaults NewNadeo uses.
+type Options struct {
+	// Audience is the Nadeo audience to authenticate against, e.g.
+	// "NadeoLiveServices" or "NadeoClubServices".
+	Audience string
+
+	// HTTPClient is used for every request the client makes. If nil, a
+	// client is built from MaxIdleConns, MaxConnsPerHost and IdleConnTimeout
+	// below.
+	HTTPClient *http.Client
+
+	// MaxRetries is how many times a request is retried after a 5xx or 429
+	// response before the error is returned to the caller. Defaults to 3.
+	MaxRetries int
+
+	// RateLimiter throttles outgoing requests to stay within Nadeo's
+	// per-audience rate limits. Requests block on RateLimiter.Wait before
+	// being sent. Left nil, requests are not throttled client-side.
+	RateLimiter *rate.Limiter
+
+	// MaxIdleConns, MaxConnsPerHost and IdleConnTimeout tune the connection
+	// pool of the client built when HTTPClient is nil.
+	MaxIdleConns    int
+	MaxConnsPerHost int
+	IdleConnTimeout time.Duration
+
+	// RefreshSafetyMargin is how long before the token's refresh-at time
+	// CheckRefresh treats it as due for refresh, so a token doesn't expire
+	// mid-flight on a request that was issued just before it. Defaults to
+	// 30 seconds.
+	RefreshSafetyMargin time.Duration
+
+	// TokenStore persists tokens across process restarts. On construction
+	// the client attempts to load tokens for its audience from the store;
+	// every successful authentication or refresh saves back to it. Defaults
+	// to an in-memory store, i.e. no persistence across restarts.
+	TokenStore TokenStore
+}
+
+func (o Options) withDefaults() Options {
+	if o.Audience == "" {
+		o.Audience = "NadeoLiveServices"
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = defaultMaxRetries
+	}
+	if o.MaxIdleConns == 0 {
+		o.MaxIdleConns = defaultMaxIdleConns
+	}
+	if o.MaxConnsPerHost == 0 {
+		o.MaxConnsPerHost = defaultMaxConnsPerHost
+	}
+	if o.IdleConnTimeout == 0 {
+		o.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	if o.RefreshSafetyMargin == 0 {
+		o.RefreshSafetyMargin = defaultRefreshSafetyMargin
+	}
+	if o.TokenStore == nil {
+		o.TokenStore = NewMemoryTokenStore()
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = &http.Client{
+			Transport: &http.Transport{
+				MaxIdleConns:    o.MaxIdleConns,
+				MaxConnsPerHost: o.MaxConnsPerHost,
+				IdleConnTimeout: o.IdleConnTimeout,
+			},
+		}
+	}
+	return o
+}
+
+// doWithRetry sends req, retrying on 5xx and 429 responses with exponential
+// backoff and jitter. A Retry-After header on the response, if present,
+// takes priority over the computed backoff. If a rate limiter is configured
+// it is waited on before every attempt, including retries.
+func (n *nadeo) doWithRetry(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		if n.rateLimiter != nil {
+			if werr := n.rateLimiter.Wait(req.Context()); werr != nil {
+				return nil, fmt.Errorf("rate limiter: %s", werr.Error())
+			}
+		}
+
+		if attempt > 0 && req.GetBody != nil {
+			body, berr := req.GetBody()
+			if berr != nil {
+				return nil, fmt.Errorf("unable to rewind request body for retry: %s", berr.Error())
+			}
+			req.Body = body
+		}
+
+		resp, err = n.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("unable to perform request: %s", err.Error())
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return resp, nil
+		}
+
+		if attempt >= n.maxRetries {
+			return resp, nil
+		}
+
+		wait := retryDelay(resp, attempt)
+		resp.Body.Close()
+
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// retryDelay computes how long to wait before the next retry attempt,
+// preferring the server's Retry-After header and otherwise falling back to
+// exponential backoff with jitter.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if seconds, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if when, err := http.ParseTime(ra); err == nil {
+			return time.Until(when)
+		}
+	}
+
+	base := time.Duration(math.Pow(2, float64(attempt))) * 100 * time.Millisecond
+	jitter := time.Duration(rand.Int63n(int64(base)/2 + 1))
+	return base + jitter
+}
@@ -0,0 +1,56 @@
+package nadeo
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoWithRetryResendsBodyOnRetry(t *testing.T) {
+	const payload = `{"hello":"world"}`
+
+	var receivedBodies []string
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receivedBodies = append(receivedBodies, string(body))
+
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &nadeo{httpClient: server.Client(), maxRetries: defaultMaxRetries}
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unable to build request: %s", err.Error())
+	}
+
+	resp, err := n.doWithRetry(req)
+	if err != nil {
+		t.Fatalf("doWithRetry returned an error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected final status 200, got %d", resp.StatusCode)
+	}
+
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+
+	for i, got := range receivedBodies {
+		if got != payload {
+			t.Errorf("attempt %d: expected body %q, got %q", i+1, payload, got)
+		}
+	}
+}